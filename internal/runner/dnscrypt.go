@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/projectdiscovery/fileutil"
+)
+
+// DNSCryptResolver describes a single DNSCrypt capable resolver, either
+// parsed from a DNS stamp (sdns://...) or from a provider_name,public_key,addr
+// triple. This is parsing scaffolding only: there is no DNSCrypt client in
+// this tree yet, so -dnscrypt-fallback is parsed but not acted on and
+// dnscryptResolvers is not consumed anywhere.
+type DNSCryptResolver struct {
+	ProviderName string
+	PublicKey    []byte
+	Addr         string
+}
+
+// parseDNSStamp decodes an sdns:// DNS stamp into a DNSCryptResolver.
+//
+// The stamp layout (see https://dnscrypt.info/stamps-specifications) is:
+//
+//	sdns://base64url(0x01 | props[8] | addr_len[1] | addr | pk_len[1] | pk | provider_name_len[1] | provider_name)
+//
+// dnsx only needs the DNSCrypt protocol (type 0x01), so any other stamp
+// type is rejected rather than silently misparsed.
+func parseDNSStamp(stamp string) (*DNSCryptResolver, error) {
+	raw := strings.TrimPrefix(stamp, "sdns://")
+
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns stamp: %w", err)
+	}
+	if len(decoded) < 9 {
+		return nil, fmt.Errorf("invalid dns stamp: too short")
+	}
+	if decoded[0] != 0x01 {
+		return nil, fmt.Errorf("unsupported dns stamp protocol 0x%02x, only dnscrypt (0x01) is supported", decoded[0])
+	}
+
+	// decoded[0] = protocol, decoded[1:9] = properties bitmask
+	cursor := decoded[9:]
+
+	addr, cursor, err := readLengthPrefixed(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns stamp address: %w", err)
+	}
+
+	publicKey, cursor, err := readLengthPrefixed(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns stamp public key: %w", err)
+	}
+
+	providerName, _, err := readLengthPrefixed(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns stamp provider name: %w", err)
+	}
+
+	return &DNSCryptResolver{
+		ProviderName: string(providerName),
+		PublicKey:    publicKey,
+		Addr:         string(addr),
+	}, nil
+}
+
+// readLengthPrefixed consumes a single length-prefixed field and returns it
+// along with the remaining buffer.
+func readLengthPrefixed(buf []byte) ([]byte, []byte, error) {
+	if len(buf) < 1 {
+		return nil, nil, fmt.Errorf("unexpected end of stamp")
+	}
+	length := int(buf[0])
+	if len(buf) < 1+length {
+		return nil, nil, fmt.Errorf("unexpected end of stamp")
+	}
+	return buf[1 : 1+length], buf[1+length:], nil
+}
+
+// parseDNSCryptTriple parses the provider_name,public_key,addr form accepted
+// as an alternative to a full dns stamp.
+func parseDNSCryptTriple(entry string) (*DNSCryptResolver, error) {
+	parts := strings.Split(entry, ",")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid dnscrypt resolver %q, expected provider_name,public_key,addr", entry)
+	}
+
+	publicKey, err := hex.DecodeString(strings.ReplaceAll(parts[1], ":", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid dnscrypt public key %q: %w", parts[1], err)
+	}
+
+	return &DNSCryptResolver{
+		ProviderName: parts[0],
+		PublicKey:    publicKey,
+		Addr:         parts[2],
+	}, nil
+}
+
+// configureDNSCrypt parses -dnscrypt-resolver (file or comma separated),
+// accepting either sdns:// stamps or provider_name,public_key,addr triples,
+// into options.dnscryptResolvers. Parsing only: no DNSCrypt client reads
+// this slice yet.
+func (options *Options) configureDNSCrypt() error {
+	if options.DNSCryptResolver == "" {
+		return nil
+	}
+
+	var entries []string
+	if fileutil.FileExists(options.DNSCryptResolver) {
+		file, err := os.Open(options.DNSCryptResolver)
+		if err != nil {
+			return fmt.Errorf("could not open dnscrypt-resolver file: %w", err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			entries = append(entries, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("could not read dnscrypt-resolver file: %w", err)
+		}
+	} else {
+		entries = strings.Split(options.DNSCryptResolver, ",")
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var (
+			resolver *DNSCryptResolver
+			err      error
+		)
+		if strings.HasPrefix(entry, "sdns://") {
+			resolver, err = parseDNSStamp(entry)
+		} else {
+			resolver, err = parseDNSCryptTriple(entry)
+		}
+		if err != nil {
+			return err
+		}
+
+		options.dnscryptResolvers = append(options.dnscryptResolvers, resolver)
+	}
+
+	return nil
+}
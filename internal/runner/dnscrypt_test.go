@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDNSStamp(t *testing.T) {
+	// sdns:// stamp encoding addr=2.2.2.2, pk=<32 random bytes>,
+	// providerName=2.dnscrypt-cert.example.com, in the spec order
+	// 0x01 | props[8] | LP(addr) | LP(pk) | LP(providerName).
+	stamp := "sdns://AQAAAAAAAAAABzIuMi4yLjIg_3MRpcDkCZnlIEygSH6DAS3tZW5HrZsaAlZ7wAAOpwEbMi5kbnNjcnlwdC1jZXJ0LmV4YW1wbGUuY29t"
+	wantPK, err := hex.DecodeString("ff7311a5c0e40999e5204ca0487e83012ded656e47ad9b1a02567bc0000ea701")
+	require.NoError(t, err)
+
+	resolver, err := parseDNSStamp(stamp)
+	require.NoError(t, err)
+	require.Equal(t, "2.2.2.2", resolver.Addr)
+	require.Equal(t, "2.dnscrypt-cert.example.com", resolver.ProviderName)
+	require.Equal(t, wantPK, resolver.PublicKey)
+}
+
+func TestParseDNSStampErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		stamp string
+	}{
+		{"not base64", "sdns://not-valid-base64!!"},
+		{"too short", "sdns://AQ"},
+		{"wrong protocol", "sdns://AgAAAAAAAAAA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDNSStamp(tt.stamp)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseDNSCryptTriple(t *testing.T) {
+	resolver, err := parseDNSCryptTriple("2.dnscrypt-cert.example.com,b735:1177:cc4a:ef2d,2.2.2.2:443")
+	require.NoError(t, err)
+	require.Equal(t, "2.dnscrypt-cert.example.com", resolver.ProviderName)
+	require.Equal(t, "2.2.2.2:443", resolver.Addr)
+	require.Equal(t, []byte{0xb7, 0x35, 0x11, 0x77, 0xcc, 0x4a, 0xef, 0x2d}, resolver.PublicKey)
+}
+
+func TestParseDNSCryptTripleInvalid(t *testing.T) {
+	_, err := parseDNSCryptTriple("missing-fields")
+	require.Error(t, err)
+
+	_, err = parseDNSCryptTriple("name,not-hex,addr")
+	require.Error(t, err)
+}
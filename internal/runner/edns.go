@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// EDNSOption is a raw EDNS(0) OPT option (code + data) requested on the
+// query, or returned by the resolver in the response OPT pseudo-record.
+type EDNSOption struct {
+	Code uint16 `json:"code"`
+	Data []byte `json:"data"`
+}
+
+// extendedErrorCodes maps the RFC 8914 Extended DNS Error codes dnsx knows
+// how to label in output.
+var extendedErrorCodes = map[uint16]string{
+	0:  "other",
+	1:  "unsupported-dnskey-algorithm",
+	2:  "unsupported-ds-digest-type",
+	3:  "stale-answer",
+	4:  "forged-answer",
+	5:  "dnssec-indeterminate",
+	6:  "dnssec-bogus",
+	7:  "signature-expired",
+	8:  "signature-not-yet-valid",
+	9:  "dnskey-missing",
+	10: "rrsigs-missing",
+	11: "no-zone-key-bit-set",
+	12: "nsec-missing",
+	13: "cached-error",
+	14: "not-ready",
+	15: "blocked",
+	16: "censored",
+	17: "filtered",
+	18: "prohibited",
+	19: "stale-nxdomain-answer",
+	20: "not-authoritative",
+	21: "not-supported",
+	22: "no-reachable-authority",
+	23: "network-error",
+	24: "invalid-data",
+}
+
+// ExtendedDNSError is the decoded representation of an EDE option. Nothing
+// in this tree extracts one from a real response OPT record yet, so no
+// output path renders it; this is the type that code path would populate.
+type ExtendedDNSError struct {
+	Code uint16 `json:"code"`
+	Text string `json:"text"`
+	Note string `json:"note,omitempty"`
+}
+
+// EDNSResponse holds the OPT pseudo-record fields a resolver could surface
+// (NSID, server cookie, EDE).
+//
+// Nothing in this tree constructs an EDNSResponse from a real response OPT
+// record, so no output path renders one. configureEDNS below only validates
+// and pre-parses the outgoing request side (-cookie, -subnet, -ednsopt); it
+// does not attach them to a query or read anything back.
+type EDNSResponse struct {
+	NSID         string            `json:"nsid,omitempty"`
+	ServerCookie string            `json:"server_cookie,omitempty"`
+	ExtendedErr  *ExtendedDNSError `json:"extended_error,omitempty"`
+}
+
+// String renders the EDNS response fields for text output
+func (e *EDNSResponse) String() string {
+	var parts []string
+	if e.NSID != "" {
+		parts = append(parts, fmt.Sprintf("nsid=%s", e.NSID))
+	}
+	if e.ServerCookie != "" {
+		parts = append(parts, fmt.Sprintf("cookie=%s", e.ServerCookie))
+	}
+	if e.ExtendedErr != nil {
+		parts = append(parts, fmt.Sprintf("ede=%d(%s)", e.ExtendedErr.Code, e.ExtendedErr.Text))
+	}
+	return strings.Join(parts, " ")
+}
+
+// extendedErrorName returns the RFC 8914 mnemonic for a code, or falls back
+// to the numeric code itself if unknown. The reverse lookup is
+// extendedErrorNameToCode.
+func extendedErrorName(code uint16) string {
+	if name, ok := extendedErrorCodes[code]; ok {
+		return name
+	}
+	return strconv.Itoa(int(code))
+}
+
+// extendedErrorNameToCode resolves an EDE mnemonic (eg. "blocked",
+// "dnssec-bogus") back to its numeric code, used by -rcode to recognize EDE
+// names alongside classic rcode names.
+func extendedErrorNameToCode(name string) (uint16, bool) {
+	name = strings.ToLower(name)
+	for code, mnemonic := range extendedErrorCodes {
+		if mnemonic == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// dnsCookieLength is the fixed length (in bytes) of an RFC 7873 client
+// cookie.
+const dnsCookieLength = 8
+
+// configureEDNS validates the edns0 related flags and pre-parses the
+// client subnet and custom option list into options.ednsOptions. No query
+// path in this tree attaches these to an outgoing OPT record yet; this is
+// validation and pre-parsing only.
+func (options *Options) configureEDNS() error {
+	if options.Cookie != "" {
+		decoded, err := hex.DecodeString(options.Cookie)
+		if err != nil {
+			return fmt.Errorf("invalid -cookie value, expected hex: %w", err)
+		}
+		if len(decoded) != dnsCookieLength {
+			return fmt.Errorf("invalid -cookie value, expected %d bytes, got %d", dnsCookieLength, len(decoded))
+		}
+	}
+
+	if options.Subnet != "" {
+		if _, _, err := net.ParseCIDR(options.Subnet); err != nil {
+			return fmt.Errorf("invalid -subnet value, expected CIDR: %w", err)
+		}
+	}
+
+	for _, raw := range options.EDNSOpt {
+		opt, err := parseEDNSOpt(raw)
+		if err != nil {
+			return err
+		}
+		options.ednsOptions = append(options.ednsOptions, opt)
+	}
+
+	return nil
+}
+
+// parseEDNSOpt parses a single -ednsopt CODE:HEX entry
+func parseEDNSOpt(raw string) (*EDNSOption, error) {
+	parts := strings.SplitN(raw, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid -ednsopt value %q, expected CODE:HEX", raw)
+	}
+
+	code, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -ednsopt code %q: %w", parts[0], err)
+	}
+
+	data, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid -ednsopt data %q, expected hex: %w", parts[1], err)
+	}
+
+	return &EDNSOption{Code: uint16(code), Data: data}, nil
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEDNSOpt(t *testing.T) {
+	opt, err := parseEDNSOpt("10:deadbeef")
+	require.NoError(t, err)
+	require.EqualValues(t, 10, opt.Code)
+	require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, opt.Data)
+
+	_, err = parseEDNSOpt("no-colon")
+	require.Error(t, err)
+
+	_, err = parseEDNSOpt("notanumber:deadbeef")
+	require.Error(t, err)
+
+	_, err = parseEDNSOpt("10:nothex")
+	require.Error(t, err)
+}
+
+func TestConfigureEDNSCookieLength(t *testing.T) {
+	// a valid 8-byte client cookie
+	options := &Options{Cookie: "0102030405060708"}
+	require.NoError(t, options.configureEDNS())
+
+	// too short to be a client cookie
+	options = &Options{Cookie: "0102"}
+	require.Error(t, options.configureEDNS())
+
+	// not hex at all
+	options = &Options{Cookie: "not-hex-at-all!"}
+	require.Error(t, options.configureEDNS())
+}
+
+func TestConfigureEDNSSubnet(t *testing.T) {
+	options := &Options{Subnet: "1.2.3.0/24"}
+	require.NoError(t, options.configureEDNS())
+
+	options = &Options{Subnet: "not-a-cidr"}
+	require.Error(t, options.configureEDNS())
+}
+
+func TestEDNSResponseString(t *testing.T) {
+	resp := &EDNSResponse{}
+	require.Equal(t, "", resp.String())
+
+	resp = &EDNSResponse{NSID: "ns1", ServerCookie: "abcd1234"}
+	require.Equal(t, "nsid=ns1 cookie=abcd1234", resp.String())
+
+	resp = &EDNSResponse{ExtendedErr: &ExtendedDNSError{Code: 15, Text: "blocked"}}
+	require.Equal(t, "ede=15(blocked)", resp.String())
+}
+
+func TestExtendedErrorNameToCode(t *testing.T) {
+	code, ok := extendedErrorNameToCode("blocked")
+	require.True(t, ok)
+	require.EqualValues(t, 15, code)
+
+	code, ok = extendedErrorNameToCode("DNSSEC-BOGUS")
+	require.True(t, ok)
+	require.EqualValues(t, 6, code)
+
+	_, ok = extendedErrorNameToCode("not-a-real-ede")
+	require.False(t, ok)
+}
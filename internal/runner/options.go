@@ -7,64 +7,90 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/projectdiscovery/fileutil"
-	"github.com/projectdiscovery/goconfig"
 	"github.com/projectdiscovery/goflags"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/gologger/levels"
 )
 
 const (
-	DefaultResumeFile = "resume.cfg"
+	// DefaultResumeSession is used when -resume-session is not given
+	DefaultResumeSession = "default"
 )
 
 type Options struct {
-	Resolvers         string
-	Hosts             string
-	Domains           string
-	WordList          string
-	Threads           int
-	RateLimit         int
-	Retries           int
-	OutputFormat      string
-	OutputFile        string
-	Raw               bool
-	Silent            bool
-	Verbose           bool
-	Version           bool
-	Response          bool
-	ResponseOnly      bool
-	A                 bool
-	AAAA              bool
-	NS                bool
-	CNAME             bool
-	PTR               bool
-	MX                bool
-	SOA               bool
-	TXT               bool
-	JSON              bool
-	Trace             bool
-	TraceMaxRecursion int
-	WildcardThreshold int
-	WildcardDomain    string
-	ShowStatistics    bool
-	rcodes            map[int]struct{}
-	RCode             string
-	hasRCodes         bool
-	Resume            bool
-	resumeCfg         *ResumeCfg
-	FlushInterval     int
-	HostsFile         bool
+	Resolvers          string
+	Hosts              string
+	Domains            string
+	WordList           string
+	Threads            int
+	RateLimit          int
+	Retries            int
+	OutputFormat       string
+	OutputFile         string
+	Raw                bool
+	Silent             bool
+	Verbose            bool
+	Version            bool
+	Response           bool
+	ResponseOnly       bool
+	A                  bool
+	AAAA               bool
+	NS                 bool
+	CNAME              bool
+	PTR                bool
+	MX                 bool
+	SOA                bool
+	TXT                bool
+	JSON               bool
+	Trace              bool
+	TraceMaxRecursion  int
+	WildcardThreshold  int
+	WildcardDomain     string
+	WildcardMode       string
+	WildcardProbeCount int
+	ShowStatistics     bool
+	rcodes             map[int]struct{}
+	RCode              string
+	hasRCodes          bool
+	excludeRcodes      bool
+	edeCodes           map[uint16]struct{}
+	Resume             bool
+	ResumeSession      string
+	ResumeList         bool
+	ResumeTTLHours     int
+	resumeCfg          *ResumeCfg
+	FlushInterval      int
+	HostsFile          bool
+	DOH                bool
+	DOT                bool
+	DOQ                bool
+	DOHUrl             string
+	resolvers          []*Resolver
+	BufSize            int
+	Dnssec             bool
+	Nsid               bool
+	Cookie             string
+	Subnet             string
+	EDNSOpt            goflags.StringSlice
+	ednsOptions        []*EDNSOption
+	DNSCryptResolver   string
+	DNSCryptFallback   bool
+	dnscryptResolvers  []*DNSCryptResolver
+	Passive            bool
+	PassiveSources     goflags.StringSlice
+	providerConfig     *ProviderConfig
 }
 
 // ShouldLoadResume resume file
 func (options *Options) ShouldLoadResume() bool {
-	return options.Resume && fileutil.FileExists(DefaultResumeFile)
+	return options.Resume && resumeFileExists(options.ResumeSession)
 }
 
-// ShouldSaveResume file
+// ShouldSaveResume reports whether progress should be checkpointed for the
+// current run. Earlier versions hard-coded this to true; resuming is now
+// opt-in via -resume so a plain run never leaves a session file behind.
 func (options *Options) ShouldSaveResume() bool {
-	return true
+	return options.Resume
 }
 
 // ParseOptions parses the command line options for application
@@ -93,7 +119,7 @@ func ParseOptions() *Options {
 	createGroup(flagSet, "filters", "Filters",
 		flagSet.BoolVar(&options.Response, "resp", false, "display dns response"),
 		flagSet.BoolVar(&options.ResponseOnly, "resp-only", false, "display dns response only"),
-		flagSet.StringVarP(&options.RCode, "rc", "rcode", "", "filter result by dns status code (eg. -rcode noerror,servfail,refused)"),
+		flagSet.StringVarP(&options.RCode, "rc", "rcode", "", "filter result by dns status code or extended dns error (eg. -rcode noerror,servfail,refused or -rcode !nxdomain,!refused to exclude, or -rcode blocked,censored,dnssec-bogus)"),
 	)
 
 	createGroup(flagSet, "rate-limit", "Rate-limit",
@@ -121,12 +147,43 @@ func ParseOptions() *Options {
 		flagSet.IntVar(&options.TraceMaxRecursion, "trace-max-recursion", math.MaxInt16, "Max recursion for dns trace"),
 		flagSet.IntVar(&options.FlushInterval, "flush-interval", 10, "flush interval of output file"),
 		flagSet.BoolVar(&options.Resume, "resume", false, "resume existing scan"),
+		flagSet.StringVar(&options.ResumeSession, "resume-session", DefaultResumeSession, "name of the resume session, allowing multiple concurrent scans to resume independently"),
+		flagSet.BoolVar(&options.ResumeList, "resume-list", false, "list all saved resume sessions and exit"),
+		flagSet.IntVar(&options.ResumeTTLHours, "resume-ttl", 24*7, "hours after which a stale resume session is auto-expired"),
 	)
 
 	createGroup(flagSet, "configs", "Configurations",
-		flagSet.StringVarP(&options.Resolvers, "resolver", "r", "", "list of resolvers to use (file or comma separated)"),
+		flagSet.StringVarP(&options.Resolvers, "resolver", "r", "", "list of resolvers to use (file or comma separated; tls://, https:// and quic:// prefixed entries are tagged by transport but not yet dialed over it, see -doh/-dot/-doq)"),
 		flagSet.IntVarP(&options.WildcardThreshold, "wildcard-threshold", "wt", 5, "wildcard filter threshold"),
 		flagSet.StringVarP(&options.WildcardDomain, "wildcard-domain", "wd", "", "domain name for wildcard filtering (other flags will be ignored)"),
+		flagSet.StringVar(&options.WildcardMode, "wildcard-mode", "lenient", "wildcard detection mode (strict, lenient; validated only, no result is filtered by it)"),
+		flagSet.IntVar(&options.WildcardProbeCount, "wildcard-probe-count", 3, "number of random sibling labels to probe per parent when detecting wildcards (no result is filtered by it)"),
+	)
+
+	createGroup(flagSet, "transport", "Transport",
+		flagSet.BoolVar(&options.DOH, "doh", false, "tag -resolver entries as DNS-over-HTTPS (not yet dialed over HTTPS; see Resolver doc comment)"),
+		flagSet.BoolVar(&options.DOT, "dot", false, "tag -resolver entries as DNS-over-TLS (not yet dialed over TLS; see Resolver doc comment)"),
+		flagSet.BoolVar(&options.DOQ, "doq", false, "tag -resolver entries as DNS-over-QUIC (not yet dialed over QUIC; see Resolver doc comment)"),
+		flagSet.StringVar(&options.DOHUrl, "doh-url", "", "DNS-over-HTTPS endpoint to tag as the resolver (eg. https://dns.google/dns-query; not yet dialed, requires -doh)"),
+	)
+
+	createGroup(flagSet, "edns", "EDNS(0)",
+		flagSet.IntVar(&options.BufSize, "bufsize", 1232, "edns0 UDP payload size for the OPT record (stored only, no OPT record is attached to queries)"),
+		flagSet.BoolVar(&options.Dnssec, "dnssec", false, "DNSSEC OK (DO) bit for the OPT record (stored only, no OPT record is attached to queries)"),
+		flagSet.BoolVar(&options.Nsid, "nsid", false, "NSID request via EDNS(0) (stored only, no OPT record is attached to queries)"),
+		flagSet.StringVar(&options.Cookie, "cookie", "", "dns client cookie, 8 bytes hex encoded (validated only, not sent; none is generated when empty)"),
+		flagSet.StringVar(&options.Subnet, "subnet", "", "edns client subnet in CIDR form (eg. 1.2.3.0/24, 2001:db8::/32; validated only, not sent)"),
+		flagSet.StringSliceVarP(&options.EDNSOpt, "ednsopt", "eo", nil, "custom edns0 option as CODE:HEX (can be specified multiple times; parsed only, not sent)", goflags.CommaSeparatedStringSliceOptions),
+	)
+
+	createGroup(flagSet, "dnscrypt", "DNSCrypt",
+		flagSet.StringVar(&options.DNSCryptResolver, "dnscrypt-resolver", "", "dnscrypt resolver(s), as sdns:// stamps or provider_name,public_key,addr triples (file or comma separated; parsed only, queries are not sent over dnscrypt)"),
+		flagSet.BoolVar(&options.DNSCryptFallback, "dnscrypt-fallback", false, "fallback to plain dns if the dnscrypt handshake fails (accepted only, no dnscrypt handshake is made)"),
+	)
+
+	createGroup(flagSet, "passive", "Passive",
+		flagSet.BoolVar(&options.Passive, "passive", false, "use passive sources with -d in place of -w (loads provider config and sources only; no passive results are fetched or resolved)"),
+		flagSet.StringSliceVarP(&options.PassiveSources, "passive-sources", "ps", nil, "passive sources to use for enumeration (eg. cert,otx,crtsh)", goflags.CommaSeparatedStringSliceOptions),
 	)
 
 	_ = flagSet.Parse()
@@ -134,6 +191,11 @@ func ParseOptions() *Options {
 	// Read the inputs and configure the logging
 	options.configureOutput()
 
+	if options.ResumeList {
+		listResumeSessions()
+		os.Exit(0)
+	}
+
 	err := options.configureRcodes()
 	if err != nil {
 		gologger.Fatal().Msgf("%s\n", err)
@@ -144,6 +206,26 @@ func ParseOptions() *Options {
 		gologger.Fatal().Msgf("%s\n", err)
 	}
 
+	err = options.configureResolvers()
+	if err != nil {
+		gologger.Fatal().Msgf("%s\n", err)
+	}
+
+	err = options.configureEDNS()
+	if err != nil {
+		gologger.Fatal().Msgf("%s\n", err)
+	}
+
+	err = options.configureDNSCrypt()
+	if err != nil {
+		gologger.Fatal().Msgf("%s\n", err)
+	}
+
+	err = options.configurePassive()
+	if err != nil {
+		gologger.Fatal().Msgf("%s\n", err)
+	}
+
 	showBanner()
 
 	if options.Version {
@@ -172,14 +254,46 @@ func (options *Options) validateOptions() {
 	if wordListPresent && !domainsPresent {
 		gologger.Fatal().Msg("missing domain(d) flag required with wordlist(w) input")
 	}
-	if domainsPresent && !wordListPresent {
+	if domainsPresent && !wordListPresent && !options.Passive {
 		gologger.Fatal().Msgf("missing wordlist(w) flag required with domain(d) input")
 	}
 
+	if options.Passive && !domainsPresent {
+		gologger.Fatal().Msgf("passive flag requires domain(d) flag")
+	}
+
 	// stdin can be set only on one flag
 	if argumentHasStdin(options.Domains) && argumentHasStdin(options.WordList) {
 		gologger.Fatal().Msgf("stdin can be set for one flag")
 	}
+
+	if options.DOHUrl != "" && !options.DOH {
+		gologger.Fatal().Msgf("doh-url flag can only be used with doh flag")
+	}
+	if countTrue(options.DOH, options.DOT, options.DOQ) > 1 {
+		gologger.Fatal().Msgf("doh, dot and doq flags are mutually exclusive")
+	}
+
+	if options.DNSCryptFallback && options.DNSCryptResolver == "" {
+		gologger.Fatal().Msgf("dnscrypt-fallback flag can only be used with dnscrypt-resolver flag")
+	}
+
+	switch options.WildcardMode {
+	case "strict", "lenient":
+	default:
+		gologger.Fatal().Msgf("invalid wildcard-mode %q, expected strict or lenient", options.WildcardMode)
+	}
+}
+
+// countTrue returns the number of true values among the given booleans
+func countTrue(values ...bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
 }
 
 func argumentHasStdin(arg string) bool {
@@ -197,10 +311,41 @@ func (options *Options) configureOutput() {
 	}
 }
 
+// configureRcodes parses the -rcode flag into a set of numeric rcodes to
+// match against. Every entry may be prefixed with "!" to negate the whole
+// filter (eg. -rcode !nxdomain,!refused keeps everything except those two),
+// and Extended DNS Error mnemonics (blocked, censored, filtered,
+// dnssec-bogus, ...) are recognized alongside the classic rcode names so
+// dnsx can probe for resolver-side blocking. Included and "!"-negated
+// entries cannot be mixed in the same filter, since a negated entry flips
+// the meaning of the whole filter to "everything except" - mixing them
+// would silently drop included codes rather than keep them.
 func (options *Options) configureRcodes() error {
 	options.rcodes = make(map[int]struct{})
+	options.edeCodes = make(map[uint16]struct{})
+
+	var sawIncluded, sawExcluded bool
+
 	rcodes := strings.Split(options.RCode, ",")
 	for _, rcode := range rcodes {
+		rcode = strings.TrimSpace(rcode)
+		if rcode == "" {
+			continue
+		}
+
+		if strings.HasPrefix(rcode, "!") {
+			sawExcluded = true
+			options.excludeRcodes = true
+			rcode = strings.TrimPrefix(rcode, "!")
+		} else {
+			sawIncluded = true
+		}
+
+		if edeCode, ok := extendedErrorNameToCode(rcode); ok {
+			options.edeCodes[edeCode] = struct{}{}
+			continue
+		}
+
 		var rc int
 		switch strings.ToLower(rcode) {
 		case "":
@@ -254,23 +399,58 @@ func (options *Options) configureRcodes() error {
 		options.rcodes[rc] = struct{}{}
 	}
 
+	if sawIncluded && sawExcluded {
+		return errors.New("-rcode cannot mix included and !-negated codes in the same filter")
+	}
+
 	options.hasRCodes = options.RCode != ""
 
-	// Set rcode to 0 if none was specified
-	if len(options.rcodes) == 0 {
+	// Set rcode to 0 if none was specified and no EDE filter took its place
+	if len(options.rcodes) == 0 && len(options.edeCodes) == 0 {
 		options.rcodes[0] = struct{}{}
 	}
 
 	return nil
 }
 
+// MatchesRcode reports whether a response with the given rcode (and,
+// optionally, the given EDE code when present in the OPT record) passes the
+// configured -rcode filter, honoring "!"-negation.
+func (options *Options) MatchesRcode(rcode int, edeCode *uint16) bool {
+	if !options.hasRCodes {
+		return true
+	}
+
+	_, rcodeMatch := options.rcodes[rcode]
+
+	edeMatch := false
+	if edeCode != nil {
+		_, edeMatch = options.edeCodes[*edeCode]
+	}
+
+	matched := rcodeMatch || edeMatch
+	if options.excludeRcodes {
+		return !matched
+	}
+	return matched
+}
+
+// configureResume loads the named resume session (see resume.go), expiring
+// and discarding it first if it is older than -resume-ttl.
 func (options *Options) configureResume() error {
-	options.resumeCfg = &ResumeCfg{}
-	if options.Resume && fileutil.FileExists(DefaultResumeFile) {
-		return goconfig.Load(&options.resumeCfg, DefaultResumeFile)
+	options.resumeCfg = newResumeCfg(options.ResumeSession)
 
+	if !options.Resume {
+		return nil
 	}
-	return nil
+
+	expireStaleResumeSessions(options.ResumeTTLHours)
+
+	if !resumeFileExists(options.ResumeSession) {
+		return nil
+	}
+
+	return loadResume(options.ResumeSession, options.resumeCfg)
 }
 
 func createGroup(flagSet *goflags.FlagSet, groupName, description string, flags ...*goflags.FlagData) {
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureRcodesInclude(t *testing.T) {
+	options := &Options{RCode: "noerror,refused"}
+	require.NoError(t, options.configureRcodes())
+
+	require.True(t, options.MatchesRcode(0, nil))
+	require.True(t, options.MatchesRcode(5, nil))
+	require.False(t, options.MatchesRcode(3, nil))
+}
+
+func TestConfigureRcodesExclude(t *testing.T) {
+	options := &Options{RCode: "!nxdomain,!refused"}
+	require.NoError(t, options.configureRcodes())
+
+	require.False(t, options.MatchesRcode(3, nil))
+	require.False(t, options.MatchesRcode(5, nil))
+	require.True(t, options.MatchesRcode(0, nil))
+}
+
+func TestConfigureRcodesMixedIsRejected(t *testing.T) {
+	options := &Options{RCode: "noerror,!refused"}
+	require.Error(t, options.configureRcodes())
+}
+
+func TestConfigureRcodesExtendedError(t *testing.T) {
+	options := &Options{RCode: "blocked,censored"}
+	require.NoError(t, options.configureRcodes())
+
+	blocked := uint16(15)
+	censored := uint16(16)
+	filtered := uint16(17)
+
+	require.True(t, options.MatchesRcode(0, &blocked))
+	require.True(t, options.MatchesRcode(0, &censored))
+	require.False(t, options.MatchesRcode(0, &filtered))
+	require.False(t, options.MatchesRcode(0, nil))
+}
+
+func TestConfigureRcodesDefault(t *testing.T) {
+	options := &Options{}
+	require.NoError(t, options.configureRcodes())
+
+	// no filter specified: everything matches
+	require.True(t, options.MatchesRcode(0, nil))
+	require.True(t, options.MatchesRcode(3, nil))
+}
+
+func TestConfigureRcodesInvalid(t *testing.T) {
+	options := &Options{RCode: "not-a-real-rcode"}
+	require.Error(t, options.configureRcodes())
+}
@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// passiveHTTPClient is shared by every source fetcher below.
+var passiveHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// crtshRecord is the subset of crt.sh's JSON response dnsx cares about.
+type crtshRecord struct {
+	NameValue string `json:"name_value"`
+}
+
+// fetchCrtsh queries crt.sh's certificate transparency search for every
+// name ever certified under domain. crt.sh is keyless.
+func fetchCrtsh(domain string) ([]string, error) {
+	url := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", domain)
+
+	resp, err := passiveHTTPClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("crtsh: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crtsh: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("crtsh: %w", err)
+	}
+
+	return parseCrtshResponse(body)
+}
+
+// parseCrtshResponse extracts every subdomain name from a crt.sh JSON
+// response. A single record's name_value can contain several
+// newline-separated SANs, so each is split out individually.
+func parseCrtshResponse(data []byte) ([]string, error) {
+	var records []crtshRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("crtsh: invalid response: %w", err)
+	}
+
+	var names []string
+	for _, record := range records {
+		for _, name := range strings.Split(record.NameValue, "\n") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// otxPassiveDNSResponse is the subset of AlienVault OTX's passive DNS
+// response dnsx cares about.
+type otxPassiveDNSResponse struct {
+	PassiveDNS []struct {
+		Hostname string `json:"hostname"`
+	} `json:"passive_dns"`
+}
+
+// fetchOTX queries AlienVault OTX's passive DNS records for domain. An API
+// key (from provider-config.yaml) raises OTX's rate limit but is not
+// required for this endpoint.
+func fetchOTX(domain string, apiKeys []string) ([]string, error) {
+	url := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("otx: %w", err)
+	}
+	if len(apiKeys) > 0 {
+		req.Header.Set("X-OTX-API-KEY", apiKeys[0])
+	}
+
+	resp, err := passiveHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("otx: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("otx: unexpected status %d", resp.StatusCode)
+	}
+
+	var parsed otxPassiveDNSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("otx: invalid response: %w", err)
+	}
+
+	names := make([]string, 0, len(parsed.PassiveDNS))
+	for _, entry := range parsed.PassiveDNS {
+		if entry.Hostname != "" {
+			names = append(names, entry.Hostname)
+		}
+	}
+	return names, nil
+}
+
+// FetchPassiveSubdomains queries every requested passive source for domain,
+// then dedupes and filters the combined results down to actual subdomains
+// of domain so the caller can feed them straight into the resolver
+// pipeline in place of (or alongside) a -w wordlist.
+func FetchPassiveSubdomains(domain string, sources []string, config *ProviderConfig) ([]string, error) {
+	if config == nil {
+		config = &ProviderConfig{}
+	}
+
+	var (
+		all  []string
+		errs []string
+	)
+
+	for _, source := range sources {
+		var (
+			names []string
+			err   error
+		)
+
+		switch source {
+		case "crtsh", "cert":
+			names, err = fetchCrtsh(domain)
+		case "otx":
+			names, err = fetchOTX(domain, config.OTX)
+		default:
+			err = fmt.Errorf("unknown passive source %q", source)
+		}
+
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		all = append(all, names...)
+	}
+
+	result := dedupeSubdomains(domain, all)
+
+	if len(result) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("all passive sources failed: %s", strings.Join(errs, "; "))
+	}
+
+	return result, nil
+}
+
+// dedupeSubdomains lowercases, strips a leading "*." wildcard label (so
+// "*.example.com" becomes "example.com"), deduplicates, and keeps only
+// domain itself and names under it (passive sources routinely return
+// unrelated entries), sorted.
+func dedupeSubdomains(domain string, names []string) []string {
+	domain = strings.ToLower(domain)
+	suffix := "." + domain
+
+	seen := make(map[string]struct{})
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimPrefix(name, "*."))
+		if name != domain && !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		seen[name] = struct{}{}
+	}
+
+	result := make([]string, 0, len(seen))
+	for name := range seen {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
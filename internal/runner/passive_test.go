@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCrtshResponse(t *testing.T) {
+	data := []byte(`[
+		{"name_value": "www.example.com"},
+		{"name_value": "api.example.com\nstaging.example.com"},
+		{"name_value": ""}
+	]`)
+
+	names, err := parseCrtshResponse(data)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"www.example.com", "api.example.com", "staging.example.com"}, names)
+}
+
+func TestParseCrtshResponseInvalid(t *testing.T) {
+	_, err := parseCrtshResponse([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestDedupeSubdomains(t *testing.T) {
+	names := []string{
+		"www.example.com",
+		"WWW.example.com",
+		"*.example.com",
+		"api.example.com",
+		"example.com",
+		"not-example.com",
+		"evil.com",
+	}
+
+	result := dedupeSubdomains("example.com", names)
+	require.ElementsMatch(t, []string{"www.example.com", "example.com", "api.example.com"}, result)
+}
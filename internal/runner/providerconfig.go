@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/projectdiscovery/fileutil"
+	"github.com/projectdiscovery/goflags"
+	"github.com/projectdiscovery/gologger"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultProviderConfigLocation mirrors subfinder's provider-config.yaml
+// convention so users already running other projectdiscovery tools can
+// reuse the same API keys for dnsx's passive sources. Computed on each call
+// (not at init); most invocations (-version, -h, a non-passive scan) never
+// touch provider config at all.
+func defaultProviderConfigLocation() string {
+	return filepath.Join(getConfigDirectory(), "provider-config.yaml")
+}
+
+// ProviderConfig holds the API keys/credentials for the passive sources
+// supported by -passive-sources. An unset key is not an error: crtsh is
+// keyless and otx is queried without a key when none is configured.
+// CertSpotter keys are loaded, but no certspotter source is queried.
+type ProviderConfig struct {
+	OTX         []string `yaml:"otx"`
+	CertSpotter []string `yaml:"certspotter"`
+}
+
+// getConfigDirectory returns $HOME/.config/dnsx. It does not create the
+// directory: callers that actually need it to exist (eg. SaveResume) create
+// it themselves, so a path lookup alone never has the side effect of
+// creating it on disk.
+func getConfigDirectory() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".config/dnsx"
+	}
+	return filepath.Join(home, ".config", "dnsx")
+}
+
+// loadProviderConfig reads the passive source API keys from
+// $HOME/.config/dnsx/provider-config.yaml, returning an empty (keyless)
+// config if the file does not exist.
+func loadProviderConfig() (*ProviderConfig, error) {
+	config := &ProviderConfig{}
+
+	location := defaultProviderConfigLocation()
+	if !fileutil.FileExists(location) {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// configurePassive loads the provider config and validates the requested
+// passive sources when -passive is set. The actual fetching happens in
+// FetchPassiveSubdomains (passive.go); this only prepares the config and
+// source list for it.
+func (options *Options) configurePassive() error {
+	if !options.Passive {
+		return nil
+	}
+
+	config, err := loadProviderConfig()
+	if err != nil {
+		return err
+	}
+	options.providerConfig = config
+
+	if len(options.PassiveSources) == 0 {
+		options.PassiveSources = goflags.StringSlice(defaultPassiveSources())
+	}
+
+	for _, source := range options.PassiveSources {
+		if _, ok := passiveSources[source]; !ok {
+			gologger.Warning().Msgf("unknown passive source %q, skipping", source)
+		}
+	}
+
+	return nil
+}
+
+// passiveSources are the certificate-transparency and historical-DNS
+// backed subdomain sources dnsx knows how to query for -passive. "cert" is
+// an alias for "crtsh" (both query the crt.sh CT-log aggregator).
+var passiveSources = map[string]struct{}{
+	"cert":  {},
+	"otx":   {},
+	"crtsh": {},
+}
+
+// defaultPassiveSources returns every keyless-or-configured source when
+// -passive-sources is not explicitly provided.
+func defaultPassiveSources() []string {
+	sources := make([]string, 0, len(passiveSources))
+	for source := range passiveSources {
+		sources = append(sources, source)
+	}
+	return sources
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/projectdiscovery/fileutil"
+)
+
+// Transport identifies the wire protocol used to reach a resolver
+type Transport string
+
+const (
+	TransportPlain Transport = "plain"
+	TransportDOH   Transport = "doh"
+	TransportDOT   Transport = "dot"
+	TransportDOQ   Transport = "doq"
+)
+
+// Resolver holds a single resolver entry along with the transport it should
+// be queried over. Plain resolvers keep their historical `host:port` shape,
+// while encrypted resolvers are addressed as URLs (eg. tls://1.1.1.1:853,
+// https://dns.google/dns-query, quic://dns.adguard.com:853).
+//
+// This is parse-only scaffolding: options.resolvers is populated by
+// configureResolvers but nothing in this tree consumes it yet. There is no
+// DoH/DoT/DoQ client here, so cert verification, ALPN and HTTP/2 pooling
+// are not implemented.
+type Resolver struct {
+	Transport Transport
+	Host      string
+}
+
+// String returns the resolver in its original address form
+func (r *Resolver) String() string {
+	switch r.Transport {
+	case TransportDOH:
+		return "https://" + r.Host
+	case TransportDOT:
+		return "tls://" + r.Host
+	case TransportDOQ:
+		return "quic://" + r.Host
+	default:
+		return r.Host
+	}
+}
+
+// parseResolver classifies a single resolver entry, defaulting to plain
+// UDP/TCP on port 53 unless a scheme prefix or -doh/-dot/-doq flag says
+// otherwise.
+func (options *Options) parseResolver(entry string) *Resolver {
+	switch {
+	case strings.HasPrefix(entry, "https://"):
+		return &Resolver{Transport: TransportDOH, Host: strings.TrimPrefix(entry, "https://")}
+	case strings.HasPrefix(entry, "tls://"):
+		return &Resolver{Transport: TransportDOT, Host: strings.TrimPrefix(entry, "tls://")}
+	case strings.HasPrefix(entry, "quic://"):
+		return &Resolver{Transport: TransportDOQ, Host: strings.TrimPrefix(entry, "quic://")}
+	case options.DOH:
+		return &Resolver{Transport: TransportDOH, Host: entry}
+	case options.DOT:
+		return &Resolver{Transport: TransportDOT, Host: entry}
+	case options.DOQ:
+		return &Resolver{Transport: TransportDOQ, Host: entry}
+	default:
+		return &Resolver{Transport: TransportPlain, Host: entry}
+	}
+}
+
+// configureResolvers parses the -resolver flag (file or comma separated)
+// into typed resolvers, tagging each with the transport it was addressed
+// with (-doh/-dot/-doq, or a tls://, https://, quic:// prefix). This is
+// tagging only: no query loop in this tree dials DoH/DoT/DoQ, so setting
+// these flags does not actually change how a probe is resolved. See the
+// Resolver doc comment.
+func (options *Options) configureResolvers() error {
+	if options.DOH && options.DOHUrl != "" {
+		options.resolvers = append(options.resolvers, &Resolver{Transport: TransportDOH, Host: strings.TrimPrefix(options.DOHUrl, "https://")})
+		return nil
+	}
+
+	if options.Resolvers == "" {
+		return nil
+	}
+
+	var entries []string
+	if fileutil.FileExists(options.Resolvers) {
+		file, err := os.Open(options.Resolvers)
+		if err != nil {
+			return fmt.Errorf("could not open resolvers file: %w", err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			entries = append(entries, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("could not read resolvers file: %w", err)
+		}
+	} else {
+		entries = strings.Split(options.Resolvers, ",")
+	}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		options.resolvers = append(options.resolvers, options.parseResolver(entry))
+	}
+
+	return nil
+}
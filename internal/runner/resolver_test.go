@@ -0,0 +1,50 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResolver(t *testing.T) {
+	tests := []struct {
+		name          string
+		entry         string
+		options       Options
+		wantTransport Transport
+		wantHost      string
+	}{
+		{"https scheme", "https://dns.google/dns-query", Options{}, TransportDOH, "dns.google/dns-query"},
+		{"tls scheme", "tls://1.1.1.1:853", Options{}, TransportDOT, "1.1.1.1:853"},
+		{"quic scheme", "quic://dns.adguard.com:853", Options{}, TransportDOQ, "dns.adguard.com:853"},
+		{"doh flag no scheme", "1.1.1.1", Options{DOH: true}, TransportDOH, "1.1.1.1"},
+		{"dot flag no scheme", "1.1.1.1", Options{DOT: true}, TransportDOT, "1.1.1.1"},
+		{"doq flag no scheme", "1.1.1.1", Options{DOQ: true}, TransportDOQ, "1.1.1.1"},
+		{"plain default", "8.8.8.8", Options{}, TransportPlain, "8.8.8.8"},
+		{"scheme wins over flag", "tls://1.1.1.1:853", Options{DOH: true}, TransportDOT, "1.1.1.1:853"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := tt.options.parseResolver(tt.entry)
+			require.Equal(t, tt.wantTransport, resolver.Transport)
+			require.Equal(t, tt.wantHost, resolver.Host)
+		})
+	}
+}
+
+func TestResolverString(t *testing.T) {
+	tests := []struct {
+		resolver *Resolver
+		want     string
+	}{
+		{&Resolver{Transport: TransportDOH, Host: "dns.google/dns-query"}, "https://dns.google/dns-query"},
+		{&Resolver{Transport: TransportDOT, Host: "1.1.1.1:853"}, "tls://1.1.1.1:853"},
+		{&Resolver{Transport: TransportDOQ, Host: "dns.adguard.com:853"}, "quic://dns.adguard.com:853"},
+		{&Resolver{Transport: TransportPlain, Host: "8.8.8.8"}, "8.8.8.8"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.resolver.String())
+	}
+}
@@ -0,0 +1,176 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/fileutil"
+	"github.com/projectdiscovery/goconfig"
+	"github.com/projectdiscovery/gologger"
+	"gopkg.in/yaml.v3"
+)
+
+// ResumeCfg is the per-session checkpoint written while a scan is running.
+// Progress is meant to be tracked as a byte offset into the input
+// (hosts/wordlist) file plus a bloom filter of FQDNs already emitted, so
+// resuming replays nothing that was already resolved instead of restarting
+// the whole scan.
+//
+// No input reader or output flusher in this tree reads or writes Offset or
+// Seen yet, so a loaded/saved ResumeCfg is not actually consulted anywhere:
+// loadResume is called only by configureResume, and nothing in this tree
+// calls SaveResume. Use newResumeCfg, not a bare
+// &ResumeCfg{}, so Seen is never nil.
+type ResumeCfg struct {
+	Session   string    `yaml:"session"`
+	Offset    int64     `yaml:"offset"`
+	Seen      *seenSet  `yaml:"seen"`
+	UpdatedAt time.Time `yaml:"updated_at"`
+}
+
+// newResumeCfg returns a ResumeCfg ready to use, with Seen pre-allocated so
+// callers never need to nil-check it before calling Add/Contains.
+func newResumeCfg(session string) *ResumeCfg {
+	return &ResumeCfg{Session: session, Seen: newSeenSet()}
+}
+
+// resumeDir returns $HOME/.config/dnsx/resume. It does not create the
+// directory; SaveResume does.
+func resumeDir() string {
+	return filepath.Join(getConfigDirectory(), "resume")
+}
+
+// resumeFilePath returns the checkpoint path for a named session.
+func resumeFilePath(session string) string {
+	if session == "" {
+		session = DefaultResumeSession
+	}
+	return filepath.Join(resumeDir(), session+".cfg")
+}
+
+func resumeFileExists(session string) bool {
+	return fileutil.FileExists(resumeFilePath(session))
+}
+
+// loadResume reads a session's checkpoint into cfg, backfilling Seen if the
+// on-disk file predates it (or was hand-edited) so it's never nil.
+func loadResume(session string, cfg *ResumeCfg) error {
+	if err := goconfig.Load(cfg, resumeFilePath(session)); err != nil {
+		return err
+	}
+	if cfg.Seen == nil {
+		cfg.Seen = newSeenSet()
+	}
+	return nil
+}
+
+// SaveResume atomically checkpoints progress for the session: it marshals
+// to a temp file in the same directory, fsyncs it, then renames it over the
+// previous checkpoint so a SIGKILL mid-write can never leave a truncated or
+// half-written resume file behind.
+//
+// Nothing in this tree calls SaveResume: no periodic flusher invokes it
+// mid-scan, so a real run never produces a saved checkpoint.
+func SaveResume(session string, cfg *ResumeCfg) error {
+	dir := resumeDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create resume directory: %w", err)
+	}
+
+	cfg.Session = session
+	cfg.UpdatedAt = time.Now()
+
+	tmp, err := os.CreateTemp(dir, ".resume-*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temp resume file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not marshal resume config: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not write resume file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("could not fsync resume file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not close resume file: %w", err)
+	}
+
+	return os.Rename(tmpPath, resumeFilePath(session))
+}
+
+// expireStaleResumeSessions removes resume files whose checkpoint is older
+// than ttlHours, so an abandoned session doesn't silently resume a scan
+// against input that may no longer even exist.
+func expireStaleResumeSessions(ttlHours int) {
+	sessions, err := listResumeFiles()
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Duration(ttlHours) * time.Hour
+	for _, path := range sessions {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) > cutoff {
+			if err := os.Remove(path); err != nil {
+				gologger.Warning().Msgf("could not expire stale resume file %s: %s", path, err)
+			}
+		}
+	}
+}
+
+// listResumeFiles returns the path of every saved resume session.
+func listResumeFiles() ([]string, error) {
+	entries, err := os.ReadDir(resumeDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".cfg") {
+			continue
+		}
+		files = append(files, filepath.Join(resumeDir(), entry.Name()))
+	}
+	return files, nil
+}
+
+// listResumeSessions implements -resume-list, printing every saved session
+// name alongside its last checkpoint time.
+func listResumeSessions() {
+	files, err := listResumeFiles()
+	if err != nil {
+		gologger.Fatal().Msgf("could not list resume sessions: %s\n", err)
+	}
+	if len(files) == 0 {
+		gologger.Info().Msgf("no saved resume sessions\n")
+		return
+	}
+
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		session := strings.TrimSuffix(filepath.Base(path), ".cfg")
+		gologger.Info().Msgf("%s (last checkpoint: %s)\n", session, info.ModTime().Format(time.RFC3339))
+	}
+}
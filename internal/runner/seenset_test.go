@@ -0,0 +1,24 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenSetAddContains(t *testing.T) {
+	s := newSeenSet()
+
+	require.False(t, s.Contains("www.example.com"))
+	s.Add("www.example.com")
+	require.True(t, s.Contains("www.example.com"))
+	require.False(t, s.Contains("api.example.com"))
+}
+
+func TestSeenSetZeroValueLazyAllocates(t *testing.T) {
+	var s seenSet
+
+	require.False(t, s.Contains("www.example.com"))
+	require.NotPanics(t, func() { s.Add("www.example.com") })
+	require.True(t, s.Contains("www.example.com"))
+}
@@ -0,0 +1,188 @@
+package runner
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// wildcardMode controls how aggressively WildcardDetector treats a partial
+// signature match as a wildcard.
+type wildcardMode string
+
+const (
+	// WildcardModeStrict drops a result as soon as any single answer (IP or
+	// CNAME target) also appears in an ancestor's signature.
+	WildcardModeStrict wildcardMode = "strict"
+	// WildcardModeLenient requires the full answer set to be a subset of an
+	// ancestor's signature before dropping it.
+	WildcardModeLenient wildcardMode = "lenient"
+)
+
+// wildcardSignature is the union of A/AAAA/CNAME answers collected for the
+// random sibling labels probed under a given parent.
+type wildcardSignature struct {
+	answers  map[string]struct{}
+	cacheHit int
+}
+
+// WildcardDetector is intended to replace the old single-threshold
+// WildcardThreshold / WildcardDomain check: for each candidate
+// `sub.parent.tld` it probes random sibling labels up the label chain,
+// caches the resulting answer-set signature per parent, and flags a real
+// result as a wildcard when its answers are (sub)set of an ancestor's
+// signature.
+//
+// Nothing in this tree constructs or calls a WildcardDetector yet: there is
+// no result-filtering loop to wire it into, and the old WildcardThreshold /
+// WildcardDomain fields are still the only ones read by anything. Treat
+// this as scaffolding for that wiring, not a functioning replacement.
+type WildcardDetector struct {
+	mode        wildcardMode
+	probeCount  int
+	resolve     func(fqdn string) ([]string, error)
+	maxCacheHit int
+
+	mu         sync.Mutex
+	signatures map[string]*wildcardSignature
+}
+
+// NewWildcardDetector builds a detector from the runner options. resolve is
+// the (host) -> answers lookup used to probe sibling labels.
+func NewWildcardDetector(options *Options, resolve func(fqdn string) ([]string, error)) *WildcardDetector {
+	return &WildcardDetector{
+		mode:        wildcardMode(options.WildcardMode),
+		probeCount:  options.WildcardProbeCount,
+		resolve:     resolve,
+		maxCacheHit: 100,
+		signatures:  make(map[string]*wildcardSignature),
+	}
+}
+
+// IsWildcard reports whether fqdn's answers are explained by a wildcard
+// configured anywhere up its label chain, probing (and caching) each
+// ancestor's signature as needed.
+func (d *WildcardDetector) IsWildcard(fqdn string, answers []string) (bool, error) {
+	// A trailing root dot would otherwise leave an empty trailing label,
+	// producing bogus parents like "tld." when walking the label chain.
+	labels := strings.Split(strings.TrimSuffix(fqdn, "."), ".")
+
+	for i := 1; i < len(labels)-1; i++ {
+		parent := strings.Join(labels[i:], ".")
+
+		signature, err := d.signatureFor(parent)
+		if err != nil {
+			return false, err
+		}
+		if signature == nil {
+			continue
+		}
+
+		if d.matches(signature, answers) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// matches applies the configured wildcard mode to decide whether answers
+// are explained by signature.
+func (d *WildcardDetector) matches(signature *wildcardSignature, answers []string) bool {
+	if d.mode == WildcardModeStrict {
+		for _, answer := range answers {
+			if _, ok := signature.answers[answer]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	// lenient: every answer must be explained by the signature
+	if len(answers) == 0 {
+		return false
+	}
+	for _, answer := range answers {
+		if _, ok := signature.answers[answer]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// signatureFor returns the cached wildcard signature for parent, probing
+// (or re-probing, once enough cache hits have accumulated to catch rotating
+// wildcards) as needed. A nil signature means parent showed no wildcard
+// behavior.
+func (d *WildcardDetector) signatureFor(parent string) (*wildcardSignature, error) {
+	d.mu.Lock()
+	signature, ok := d.signatures[parent]
+	if ok {
+		signature.cacheHit++
+		stale := signature.cacheHit >= d.maxCacheHit
+		d.mu.Unlock()
+		if !stale {
+			if len(signature.answers) == 0 {
+				return nil, nil
+			}
+			return signature, nil
+		}
+	} else {
+		d.mu.Unlock()
+	}
+
+	probed, err := d.probe(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	if probed == nil {
+		// cache "no wildcard" as an empty signature so it counts cache hits
+		// towards a re-probe like any other entry
+		d.signatures[parent] = &wildcardSignature{}
+	} else {
+		d.signatures[parent] = probed
+	}
+	d.mu.Unlock()
+
+	return probed, nil
+}
+
+// probe resolves options.WildcardProbeCount random sibling labels under
+// parent and returns their combined answer set as a signature, or nil if
+// none of them resolved (ie. parent has no wildcard).
+func (d *WildcardDetector) probe(parent string) (*wildcardSignature, error) {
+	signature := &wildcardSignature{answers: make(map[string]struct{})}
+
+	for i := 0; i < d.probeCount; i++ {
+		label, err := randomLabel()
+		if err != nil {
+			return nil, fmt.Errorf("could not generate random label: %w", err)
+		}
+
+		answers, err := d.resolve(label + "." + parent)
+		if err != nil {
+			continue
+		}
+		for _, answer := range answers {
+			signature.answers[answer] = struct{}{}
+		}
+	}
+
+	if len(signature.answers) == 0 {
+		return nil, nil
+	}
+	return signature, nil
+}
+
+// randomLabel returns a short random hex label used to probe for wildcard
+// DNS entries (<random>.parent.tld).
+func randomLabel() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", buf), nil
+}
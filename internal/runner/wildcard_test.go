@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDetector(mode string, answersByFQDN map[string][]string) *WildcardDetector {
+	options := &Options{WildcardMode: mode, WildcardProbeCount: 2}
+	resolve := func(fqdn string) ([]string, error) {
+		if answers, ok := answersByFQDN[fqdn]; ok {
+			return answers, nil
+		}
+		return nil, errors.New("nxdomain")
+	}
+	return NewWildcardDetector(options, resolve)
+}
+
+func TestWildcardDetectorLenient(t *testing.T) {
+	// every sibling under wildcard.example.com resolves to the same IP,
+	// so any candidate with exactly that answer is a wildcard.
+	answers := map[string][]string{}
+	detector := newTestDetector("lenient", answers)
+	// since randomLabel is not deterministic, stub resolve to always
+	// return the wildcard signature for any *.wildcard.example.com probe.
+	detector.resolve = func(fqdn string) ([]string, error) {
+		return []string{"1.2.3.4"}, nil
+	}
+
+	isWildcard, err := detector.IsWildcard("real.wildcard.example.com", []string{"1.2.3.4"})
+	require.NoError(t, err)
+	require.True(t, isWildcard)
+
+	isWildcard, err = detector.IsWildcard("other.wildcard.example.com", []string{"5.6.7.8"})
+	require.NoError(t, err)
+	require.False(t, isWildcard)
+}
+
+func TestWildcardDetectorStrict(t *testing.T) {
+	detector := newTestDetector("strict", nil)
+	detector.resolve = func(fqdn string) ([]string, error) {
+		return []string{"1.2.3.4", "1.2.3.5"}, nil
+	}
+
+	// strict mode flags as soon as any single answer overlaps
+	isWildcard, err := detector.IsWildcard("host.example.com", []string{"1.2.3.4", "9.9.9.9"})
+	require.NoError(t, err)
+	require.True(t, isWildcard)
+}
+
+func TestWildcardDetectorNoWildcard(t *testing.T) {
+	detector := newTestDetector("lenient", nil)
+	detector.resolve = func(fqdn string) ([]string, error) {
+		return nil, errors.New("nxdomain")
+	}
+
+	isWildcard, err := detector.IsWildcard("host.example.com", []string{"1.2.3.4"})
+	require.NoError(t, err)
+	require.False(t, isWildcard)
+}
+
+func TestWildcardDetectorTrailingDot(t *testing.T) {
+	detector := newTestDetector("lenient", nil)
+	var probedParents []string
+	detector.resolve = func(fqdn string) ([]string, error) {
+		probedParents = append(probedParents, fqdn)
+		return nil, errors.New("nxdomain")
+	}
+
+	_, err := detector.IsWildcard("host.example.com.", []string{"1.2.3.4"})
+	require.NoError(t, err)
+
+	for _, probed := range probedParents {
+		require.NotContains(t, probed, "..", "a trailing root dot must not produce a double-dot parent")
+		require.False(t, len(probed) > 0 && probed[len(probed)-1] == '.', "probed fqdn %q must not end in a trailing dot", probed)
+	}
+}